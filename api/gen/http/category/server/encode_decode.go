@@ -11,28 +11,94 @@ import (
 	"context"
 	"errors"
 	"net/http"
+	"strconv"
+	"time"
 
 	category "github.com/tektoncd/hub/api/gen/category"
+	"github.com/tektoncd/hub/api/pkg/httpcache"
+	"github.com/tektoncd/hub/api/pkg/httperr"
 	goahttp "goa.design/goa/v3/http"
 	goa "goa.design/goa/v3/pkg"
 )
 
+// defaultListLimit is used when the request doesn't specify ?limit=.
+const defaultListLimit = 50
+
 // EncodeListResponse returns an encoder for responses returned by the category
-// list endpoint.
+// list endpoint. The response is paginated via opaque ?cursor=/&limit=
+// query parameters (advertised to the caller via a Link: rel="next"
+// header) and cacheable via a strong ETag honoring If-None-Match.
 func EncodeListResponse(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, interface{}) error {
 	return func(ctx context.Context, w http.ResponseWriter, v interface{}) error {
 		res, _ := v.(*category.ListResult)
-		enc := encoder(ctx, w)
-		body := NewListResponseBody(res)
-		w.WriteHeader(http.StatusOK)
-		return enc.Encode(body)
+
+		r, _ := httpcache.RequestFrom(ctx)
+		page, nextCursor := paginateCategories(res.Categories, r)
+		body := NewListResponseBody(&category.ListResult{Categories: page})
+
+		buf := httpcache.NewResponseBuffer()
+		if err := encoder(ctx, buf).Encode(body); err != nil {
+			return err
+		}
+
+		etag := httpcache.ETag(buf.Bytes())
+		w.Header().Set("ETag", etag)
+		if httpcache.NotModified(r, etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return nil
+		}
+
+		// category.ListResult doesn't surface the categories table's
+		// last-modified timestamp yet, so there's no real freshness
+		// window to advertise; omit Cache-Control rather than claim
+		// a max-age Hub can't actually back up.
+		if maxAge := httpcache.MaxAge(time.Time{}); maxAge != "" {
+			w.Header().Set("Cache-Control", maxAge)
+		}
+		if nextCursor != "" && r != nil {
+			w.Header().Set("Link", httpcache.NextLink(r, nextCursor))
+		}
+		return buf.Flush(w)
 	}
 }
 
+// paginateCategories slices categories according to the ?limit=/&cursor=
+// query parameters on r, returning the page and the cursor for the
+// following page, or "" if categories has been exhausted.
+func paginateCategories(categories []*category.Category, r *http.Request) ([]*category.Category, string) {
+	limit := defaultListLimit
+	var cursor string
+	if r != nil {
+		if l := r.URL.Query().Get("limit"); l != "" {
+			if n, err := strconv.Atoi(l); err == nil && n > 0 {
+				limit = n
+			}
+		}
+		cursor = r.URL.Query().Get("cursor")
+	}
+
+	offset, err := httpcache.DecodeCursor(cursor)
+	if err != nil || offset < 0 || offset > len(categories) {
+		offset = 0
+	}
+
+	end := offset + limit
+	if end > len(categories) {
+		end = len(categories)
+	}
+	page := categories[offset:end]
+
+	var next string
+	if end < len(categories) {
+		next = httpcache.EncodeCursor(end)
+	}
+	return page, next
+}
+
 // EncodeListError returns an encoder for errors returned by the list category
 // endpoint.
 func EncodeListError(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder, formatter func(err error) goahttp.Statuser) func(context.Context, http.ResponseWriter, error) error {
-	encodeError := goahttp.ErrorEncoder(encoder, formatter)
+	encodeError := httperr.ErrorEncoder(encoder)
 	return func(ctx context.Context, w http.ResponseWriter, v error) error {
 		var en ErrorNamer
 		if !errors.As(v, &en) {
@@ -42,16 +108,7 @@ func EncodeListError(encoder func(context.Context, http.ResponseWriter) goahttp.
 		case "internal-error":
 			var res *goa.ServiceError
 			errors.As(v, &res)
-			enc := encoder(ctx, w)
-			var body interface{}
-			if formatter != nil {
-				body = formatter(res)
-			} else {
-				body = NewListInternalErrorResponseBody(res)
-			}
-			w.Header().Set("goa-error", res.ErrorName())
-			w.WriteHeader(http.StatusInternalServerError)
-			return enc.Encode(body)
+			return encodeError(ctx, w, res)
 		default:
 			return encodeError(ctx, w, v)
 		}
@@ -0,0 +1,153 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	category "github.com/tektoncd/hub/api/gen/category"
+	"github.com/tektoncd/hub/api/pkg/httpcache"
+	goahttp "goa.design/goa/v3/http"
+)
+
+// jsonEncoder stands in for the goa-generated ResponseEncoder: it wraps w
+// in an encoder satisfying goahttp.Encoder (Encode(v interface{}) error).
+func jsonEncoder(ctx context.Context, w http.ResponseWriter) goahttp.Encoder {
+	return json.NewEncoder(w)
+}
+
+func listResult(n int) *category.ListResult {
+	cats := make([]*category.Category, n)
+	for i := range cats {
+		cats[i] = &category.Category{ID: i, Name: "category"}
+	}
+	return &category.ListResult{Categories: cats}
+}
+
+// TestEncodeListResponse_Pagination exercises EncodeListResponse through an
+// actual *http.Request carrying ?limit=, proving httpcache.WithRequest is read
+// rather than dead code.
+func TestEncodeListResponse_Pagination(t *testing.T) {
+	encode := EncodeListResponse(jsonEncoder)
+
+	r := httptest.NewRequest(http.MethodGet, "/categories?limit=2", nil)
+	ctx := httpcache.WithRequest(context.Background(), r)
+	w := httptest.NewRecorder()
+
+	if err := encode(ctx, w, listResult(5)); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	link := w.Header().Get("Link")
+	if link == "" {
+		t.Fatal("expected a Link: rel=\"next\" header when more pages remain")
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Fatal("expected an ETag header")
+	}
+}
+
+// TestEncodeListResponse_NoNextLinkOnLastPage proves the Link header is
+// omitted once the cursor reaches the end of the collection.
+func TestEncodeListResponse_NoNextLinkOnLastPage(t *testing.T) {
+	encode := EncodeListResponse(jsonEncoder)
+
+	r := httptest.NewRequest(http.MethodGet, "/categories?limit=50", nil)
+	ctx := httpcache.WithRequest(context.Background(), r)
+	w := httptest.NewRecorder()
+
+	if err := encode(ctx, w, listResult(3)); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if link := w.Header().Get("Link"); link != "" {
+		t.Fatalf("Link header = %q, want empty", link)
+	}
+}
+
+// TestEncodeListResponse_IfNoneMatch proves a matching If-None-Match header
+// on the incoming request short-circuits to a 304 with no body.
+func TestEncodeListResponse_IfNoneMatch(t *testing.T) {
+	encode := EncodeListResponse(jsonEncoder)
+	res := listResult(3)
+
+	// First request to learn the ETag for this body.
+	r1 := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	ctx1 := httpcache.WithRequest(context.Background(), r1)
+	w1 := httptest.NewRecorder()
+	if err := encode(ctx1, w1, res); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	etag := w1.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("expected an ETag header on the first response")
+	}
+
+	// Second request replays it via If-None-Match.
+	r2 := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	r2.Header.Set("If-None-Match", etag)
+	ctx2 := httpcache.WithRequest(context.Background(), r2)
+	w2 := httptest.NewRecorder()
+	if err := encode(ctx2, w2, res); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+
+	if w2.Code != http.StatusNotModified {
+		t.Fatalf("status = %d, want %d", w2.Code, http.StatusNotModified)
+	}
+	if w2.Body.Len() != 0 {
+		t.Fatalf("body length = %d, want 0 on a 304", w2.Body.Len())
+	}
+}
+
+// TestEncodeListResponse_NilRequest proves the encoder still writes a 200
+// response without error when no request has been stashed in the context,
+// e.g. if a future caller forgets to call httpcache.WithRequest.
+func TestEncodeListResponse_NilRequest(t *testing.T) {
+	encode := EncodeListResponse(jsonEncoder)
+	w := httptest.NewRecorder()
+
+	if err := encode(context.Background(), w, listResult(3)); err != nil {
+		t.Fatalf("encode() error = %v", err)
+	}
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+}
+
+func TestPaginateCategories(t *testing.T) {
+	cats := listResult(5).Categories
+
+	t.Run("negative cursor falls back to page 1", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/categories?cursor="+httpcache.EncodeCursor(-1)+"&limit=2", nil)
+		page, next := paginateCategories(cats, r)
+		if len(page) != 2 || page[0] != cats[0] {
+			t.Fatalf("page = %v, want first 2 categories", page)
+		}
+		if next == "" {
+			t.Fatal("expected a next cursor")
+		}
+	})
+
+	t.Run("cursor past the end falls back to page 1", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/categories?cursor="+httpcache.EncodeCursor(999)+"&limit=2", nil)
+		page, _ := paginateCategories(cats, r)
+		if len(page) != 2 || page[0] != cats[0] {
+			t.Fatalf("page = %v, want first 2 categories", page)
+		}
+	})
+
+	t.Run("no request means default limit, page 1", func(t *testing.T) {
+		page, next := paginateCategories(cats, nil)
+		if len(page) != len(cats) {
+			t.Fatalf("len(page) = %d, want %d", len(page), len(cats))
+		}
+		if next != "" {
+			t.Fatalf("next = %q, want empty", next)
+		}
+	})
+}
@@ -0,0 +1,146 @@
+// Package httpcache provides the building blocks shared by Goa HTTP
+// encoders that need conditional-request (ETag) support and opaque cursor
+// pagination: a buffered response writer so an ETag can be computed before
+// any bytes reach the client, and helpers to encode/decode cursors and
+// build RFC 5988 "next" Link headers.
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// maxAgeWindow is how long a response may be cached after lastModified,
+// once a real last-modified timestamp is available to derive it from.
+const maxAgeWindow = 60 * time.Second
+
+// ResponseBuffer is an http.ResponseWriter that captures the body and
+// headers in memory so a handler can compute an ETag over the serialized
+// response before deciding whether to write it or return 304.
+type ResponseBuffer struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+// NewResponseBuffer returns an empty ResponseBuffer.
+func NewResponseBuffer() *ResponseBuffer {
+	return &ResponseBuffer{header: make(http.Header)}
+}
+
+// Header implements http.ResponseWriter.
+func (b *ResponseBuffer) Header() http.Header { return b.header }
+
+// Write implements http.ResponseWriter.
+func (b *ResponseBuffer) Write(p []byte) (int, error) { return b.body.Write(p) }
+
+// WriteHeader implements http.ResponseWriter.
+func (b *ResponseBuffer) WriteHeader(status int) { b.status = status }
+
+// Bytes returns the buffered response body.
+func (b *ResponseBuffer) Bytes() []byte { return b.body.Bytes() }
+
+// Flush copies the buffered headers, status and body onto w. Callers
+// typically set additional headers (ETag, Cache-Control, Link) on w before
+// calling Flush.
+func (b *ResponseBuffer) Flush(w http.ResponseWriter) error {
+	for k, vs := range b.header {
+		for _, v := range vs {
+			w.Header().Add(k, v)
+		}
+	}
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	w.WriteHeader(status)
+	_, err := w.Write(b.body.Bytes())
+	return err
+}
+
+// ETag computes a strong ETag over body.
+func ETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return fmt.Sprintf(`"%x"`, sum[:8])
+}
+
+// requestCtxKey is the context key under which the incoming *http.Request is
+// stashed so an encoder can read conditional-request and query-string
+// headers that goa doesn't otherwise thread through to the encode step.
+type requestCtxKey struct{}
+
+// WithRequest returns a context carrying r for later retrieval by
+// RequestFrom.
+func WithRequest(ctx context.Context, r *http.Request) context.Context {
+	return context.WithValue(ctx, requestCtxKey{}, r)
+}
+
+// RequestFrom returns the *http.Request stashed by WithRequest, if any.
+func RequestFrom(ctx context.Context) (*http.Request, bool) {
+	r, ok := ctx.Value(requestCtxKey{}).(*http.Request)
+	return r, ok
+}
+
+// NotModified reports whether r carries an If-None-Match header matching
+// etag.
+func NotModified(r *http.Request, etag string) bool {
+	if r == nil {
+		return false
+	}
+	return r.Header.Get("If-None-Match") == etag
+}
+
+// MaxAge returns the Cache-Control max-age directive derived from
+// lastModified, or "" if lastModified is the zero value. Callers must not
+// set a Cache-Control header when MaxAge returns "": the category service
+// doesn't yet surface the categories table's last-modified timestamp, and
+// faking a freshness window would tell clients the response is safe to
+// cache for longer than Hub actually knows it is.
+func MaxAge(lastModified time.Time) string {
+	if lastModified.IsZero() {
+		return ""
+	}
+	age := int(maxAgeWindow.Seconds()) - int(time.Since(lastModified).Seconds())
+	if age < 0 {
+		age = 0
+	}
+	return fmt.Sprintf("max-age=%d", age)
+}
+
+// EncodeCursor produces the opaque cursor for offset.
+func EncodeCursor(offset int) string {
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], uint64(offset))
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// DecodeCursor parses a cursor produced by EncodeCursor back into an
+// offset. An empty cursor decodes to offset 0 so the first page can be
+// requested without one.
+func DecodeCursor(cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) != 8 {
+		return 0, errors.New("httpcache: invalid cursor")
+	}
+	return int(binary.BigEndian.Uint64(b)), nil
+}
+
+// NextLink builds the RFC 5988 Link header value pointing at the next page
+// of r's request, replacing its cursor query parameter with nextCursor.
+func NextLink(r *http.Request, nextCursor string) string {
+	u := *r.URL
+	q := u.Query()
+	q.Set("cursor", nextCursor)
+	u.RawQuery = q.Encode()
+	return fmt.Sprintf(`<%s>; rel="next"`, u.RequestURI())
+}
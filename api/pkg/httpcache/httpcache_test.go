@@ -0,0 +1,134 @@
+package httpcache
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestEncodeDecodeCursor(t *testing.T) {
+	tests := []int{0, 1, 50, 999999}
+	for _, offset := range tests {
+		got, err := DecodeCursor(EncodeCursor(offset))
+		if err != nil {
+			t.Fatalf("DecodeCursor(EncodeCursor(%d)) error = %v", offset, err)
+		}
+		if got != offset {
+			t.Fatalf("DecodeCursor(EncodeCursor(%d)) = %d", offset, got)
+		}
+	}
+}
+
+func TestDecodeCursor_Empty(t *testing.T) {
+	got, err := DecodeCursor("")
+	if err != nil || got != 0 {
+		t.Fatalf("DecodeCursor(\"\") = (%d, %v), want (0, nil)", got, err)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	tests := []string{"not-base64!!", "YQ", "a-very-long-garbage-string-that-is-not-a-cursor"}
+	for _, cursor := range tests {
+		if _, err := DecodeCursor(cursor); err == nil {
+			t.Fatalf("DecodeCursor(%q) error = nil, want an error", cursor)
+		}
+	}
+}
+
+func TestDecodeCursor_NegativeRoundTrips(t *testing.T) {
+	// EncodeCursor(-1) must round-trip to a negative offset so callers can
+	// reject it explicitly instead of panicking on a negative slice index.
+	got, err := DecodeCursor(EncodeCursor(-1))
+	if err != nil {
+		t.Fatalf("DecodeCursor error = %v", err)
+	}
+	if got >= 0 {
+		t.Fatalf("DecodeCursor(EncodeCursor(-1)) = %d, want a negative offset", got)
+	}
+}
+
+func TestETag_Deterministic(t *testing.T) {
+	body := []byte(`{"categories":[]}`)
+	if ETag(body) != ETag(body) {
+		t.Fatal("ETag should be deterministic for the same body")
+	}
+	if ETag(body) == ETag([]byte(`{"categories":[1]}`)) {
+		t.Fatal("ETag should differ for different bodies")
+	}
+}
+
+func TestNotModified(t *testing.T) {
+	etag := ETag([]byte("hello"))
+
+	r := httptest.NewRequest("GET", "/", nil)
+	r.Header.Set("If-None-Match", etag)
+	if !NotModified(r, etag) {
+		t.Fatal("expected NotModified to be true when If-None-Match matches")
+	}
+
+	r2 := httptest.NewRequest("GET", "/", nil)
+	r2.Header.Set("If-None-Match", `"different"`)
+	if NotModified(r2, etag) {
+		t.Fatal("expected NotModified to be false when If-None-Match doesn't match")
+	}
+
+	if NotModified(nil, etag) {
+		t.Fatal("expected NotModified to be false for a nil request")
+	}
+}
+
+func TestWithRequestAndRequestFrom(t *testing.T) {
+	r := httptest.NewRequest("GET", "/categories?limit=5", nil)
+	ctx := WithRequest(context.Background(), r)
+
+	got, ok := RequestFrom(ctx)
+	if !ok || got != r {
+		t.Fatalf("RequestFrom() = (%v, %v), want (%v, true)", got, ok, r)
+	}
+
+	if _, ok := RequestFrom(context.Background()); ok {
+		t.Fatal("expected RequestFrom to report false when no request was stashed")
+	}
+}
+
+func TestMaxAge_ZeroLastModifiedOmitsHeader(t *testing.T) {
+	if got := MaxAge(time.Time{}); got != "" {
+		t.Fatalf("MaxAge(zero) = %q, want \"\"", got)
+	}
+}
+
+func TestMaxAge_DerivedFromLastModified(t *testing.T) {
+	got := MaxAge(time.Now().Add(-10 * time.Second))
+	if got != "max-age=50" {
+		t.Fatalf("MaxAge(10s ago) = %q, want %q", got, "max-age=50")
+	}
+}
+
+func TestMaxAge_StaleLastModifiedFloorsAtZero(t *testing.T) {
+	got := MaxAge(time.Now().Add(-time.Hour))
+	if got != "max-age=0" {
+		t.Fatalf("MaxAge(1h ago) = %q, want %q", got, "max-age=0")
+	}
+}
+
+func TestResponseBufferFlush(t *testing.T) {
+	buf := NewResponseBuffer()
+	buf.Header().Set("X-Test", "1")
+	buf.WriteHeader(201)
+	_, _ = buf.Write([]byte("body"))
+
+	w := httptest.NewRecorder()
+	if err := buf.Flush(w); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	if w.Code != 201 {
+		t.Fatalf("status = %d, want 201", w.Code)
+	}
+	if w.Header().Get("X-Test") != "1" {
+		t.Fatal("expected buffered header to be copied onto w")
+	}
+	if w.Body.String() != "body" {
+		t.Fatalf("body = %q, want %q", w.Body.String(), "body")
+	}
+}
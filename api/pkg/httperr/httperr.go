@@ -0,0 +1,111 @@
+// Package httperr provides the structured JSON error envelope shared by
+// every Goa HTTP endpoint in the Hub API, along with a panic-recovery
+// handler that returns the same envelope instead of crashing the
+// goroutine. Both read the request ID set by middleware.GenerateRequestID
+// so a client can correlate a failure with the server logs.
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"runtime/debug"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+
+	goahttp "goa.design/goa/v3/http"
+)
+
+// Envelope is the structured body returned for every error response.
+type Envelope struct {
+	Status    int         `json:"status"`
+	Error     string      `json:"error"`
+	Message   string      `json:"message"`
+	Details   interface{} `json:"details,omitempty"`
+	Code      string      `json:"code"`
+	RequestID string      `json:"request_id"`
+}
+
+// statusForCode maps a goa.ServiceError name to the HTTP status it should
+// produce. Names follow the hyphenated convention already used by the
+// category service's "internal-error" (see EncodeListError). Codes that
+// aren't recognised fall back to 500 since they indicate a failure the
+// caller can't have anticipated.
+func statusForCode(name string) int {
+	switch name {
+	case "bad-request", "invalid-request":
+		return http.StatusBadRequest
+	case "not-found":
+		return http.StatusNotFound
+	case "unauthorized":
+		return http.StatusUnauthorized
+	case "forbidden":
+		return http.StatusForbidden
+	case "internal-error":
+		return http.StatusInternalServerError
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// NewEnvelope builds the Envelope for err, tagging it with the request ID
+// stored in ctx by middleware.GenerateRequestID.
+func NewEnvelope(ctx context.Context, err error) *Envelope {
+	reqID, _ := ctx.Value(goamiddleware.RequestIDKey).(string)
+
+	env := &Envelope{
+		RequestID: reqID,
+		Message:   err.Error(),
+		Code:      "internal-error",
+		Status:    http.StatusInternalServerError,
+	}
+	var svcErr *goa.ServiceError
+	if errors.As(err, &svcErr) {
+		env.Code = svcErr.Name
+		env.Status = statusForCode(svcErr.Name)
+	}
+	env.Error = http.StatusText(env.Status)
+	return env
+}
+
+// ErrorEncoder wraps encoder so any error returned by a Goa HTTP endpoint is
+// rendered as an Envelope rather than the endpoint's own New*ResponseBody.
+func ErrorEncoder(encoder func(context.Context, http.ResponseWriter) goahttp.Encoder) func(context.Context, http.ResponseWriter, error) error {
+	return func(ctx context.Context, w http.ResponseWriter, err error) error {
+		env := NewEnvelope(ctx, err)
+		w.Header().Set("goa-error", env.Code)
+		w.WriteHeader(env.Status)
+		return encoder(ctx, w).Encode(env)
+	}
+}
+
+// Recover wraps h so a panic in a downstream handler is logged with its
+// stack trace and the request ID, and turned into a 500 Envelope instead of
+// crashing the server.
+func Recover(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			reqID, _ := r.Context().Value(goamiddleware.RequestIDKey).(string)
+			log.Printf("panic handling request %s: %v\n%s", reqID, rec, debug.Stack())
+
+			env := &Envelope{
+				Status:    http.StatusInternalServerError,
+				Error:     http.StatusText(http.StatusInternalServerError),
+				Message:   "internal server error",
+				Code:      "internal-error",
+				RequestID: reqID,
+			}
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(env.Status)
+			_ = json.NewEncoder(w).Encode(env)
+		}()
+		h.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,137 @@
+package httperr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+	goa "goa.design/goa/v3/pkg"
+)
+
+func TestStatusForCode(t *testing.T) {
+	tests := []struct {
+		name string
+		want int
+	}{
+		{"bad-request", http.StatusBadRequest},
+		{"invalid-request", http.StatusBadRequest},
+		{"not-found", http.StatusNotFound},
+		{"unauthorized", http.StatusUnauthorized},
+		{"forbidden", http.StatusForbidden},
+		{"internal-error", http.StatusInternalServerError},
+		{"some-unrecognised-code", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusForCode(tt.name); got != tt.want {
+				t.Errorf("statusForCode(%q) = %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewEnvelope_ServiceError(t *testing.T) {
+	ctx := context.WithValue(context.Background(), goamiddleware.RequestIDKey, "req-123")
+	err := &goa.ServiceError{Name: "not-found", Message: "category not found"}
+
+	env := NewEnvelope(ctx, err)
+
+	if env.Status != http.StatusNotFound {
+		t.Errorf("Status = %d, want %d", env.Status, http.StatusNotFound)
+	}
+	if env.Code != "not-found" {
+		t.Errorf("Code = %q, want %q", env.Code, "not-found")
+	}
+	if env.RequestID != "req-123" {
+		t.Errorf("RequestID = %q, want %q", env.RequestID, "req-123")
+	}
+	if env.Message != err.Error() {
+		t.Errorf("Message = %q, want %q", env.Message, err.Error())
+	}
+}
+
+func TestNewEnvelope_WrappedServiceError(t *testing.T) {
+	ctx := context.Background()
+	svcErr := &goa.ServiceError{Name: "forbidden", Message: "no access"}
+	wrapped := fmt.Errorf("list categories: %w", svcErr)
+
+	env := NewEnvelope(ctx, wrapped)
+
+	if env.Status != http.StatusForbidden {
+		t.Errorf("Status = %d, want %d", env.Status, http.StatusForbidden)
+	}
+	if env.Code != "forbidden" {
+		t.Errorf("Code = %q, want %q", env.Code, "forbidden")
+	}
+}
+
+func TestNewEnvelope_GenericError(t *testing.T) {
+	env := NewEnvelope(context.Background(), errors.New("boom"))
+
+	if env.Status != http.StatusInternalServerError {
+		t.Errorf("Status = %d, want %d", env.Status, http.StatusInternalServerError)
+	}
+	if env.Code != "internal-error" {
+		t.Errorf("Code = %q, want %q", env.Code, "internal-error")
+	}
+	if env.Message != "boom" {
+		t.Errorf("Message = %q, want %q", env.Message, "boom")
+	}
+}
+
+func TestNewEnvelope_NoRequestID(t *testing.T) {
+	env := NewEnvelope(context.Background(), errors.New("boom"))
+	if env.RequestID != "" {
+		t.Errorf("RequestID = %q, want empty when none was stashed in context", env.RequestID)
+	}
+}
+
+func TestRecover_CatchesPanicAndReturnsEnvelope(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	ctx := context.WithValue(context.Background(), goamiddleware.RequestIDKey, "req-123")
+	r := httptest.NewRequest(http.MethodGet, "/categories", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusInternalServerError)
+	}
+	var env Envelope
+	if err := json.NewDecoder(w.Body).Decode(&env); err != nil {
+		t.Fatalf("decode response body: %v", err)
+	}
+	if env.Code != "internal-error" {
+		t.Errorf("Code = %q, want %q", env.Code, "internal-error")
+	}
+	if env.RequestID != "req-123" {
+		t.Errorf("RequestID = %q, want %q", env.RequestID, "req-123")
+	}
+}
+
+func TestRecover_PassesThroughWithoutPanic(t *testing.T) {
+	h := Recover(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte("ok"))
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/categories", nil)
+	w := httptest.NewRecorder()
+
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if w.Body.String() != "ok" {
+		t.Errorf("body = %q, want %q", w.Body.String(), "ok")
+	}
+}
@@ -0,0 +1,212 @@
+package middleware
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+// durationBuckets are the histogram boundaries (in seconds) tracked for
+// hub_http_request_duration_seconds, matching Prometheus's own
+// client_golang DefBuckets.
+var durationBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// metrics is the process-wide registry backing Metrics/MetricsHandler. The
+// Hub API has no dependency on prometheus/client_golang, so it's
+// implemented directly against the Prometheus text exposition format using
+// only the standard library.
+var metrics = newRegistry()
+
+// Metrics instruments h with hub_http_requests_total,
+// hub_http_request_duration_seconds and hub_http_in_flight for the given
+// route, attaching the request ID from context as an exemplar comment on
+// the duration observation so a slow-request Grafana panel can jump
+// straight to the correlated log line.
+func Metrics(route string, h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		metrics.incInFlight(route)
+		defer metrics.decInFlight(route)
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		requestID, _ := r.Context().Value(goamiddleware.RequestIDKey).(string)
+		metrics.observe(route, r.Method, rec.status, time.Since(start).Seconds(), requestID)
+	})
+}
+
+// MetricsHandler exposes the registered metrics for scraping at /metrics in
+// the Prometheus text exposition format.
+func MetricsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		metrics.writeTo(w)
+	})
+}
+
+// statusRecorder captures the status code written by the wrapped handler so
+// it can be reported as the status label of hub_http_requests_total after
+// ServeHTTP returns.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// counterKey identifies one hub_http_requests_total series.
+type counterKey struct {
+	route, method, status string
+}
+
+// durationKey identifies one hub_http_request_duration_seconds series.
+type durationKey struct {
+	route, method string
+}
+
+// registry holds the in-memory series for all three metrics. It's
+// intentionally minimal: counters and gauges as plain maps, and a
+// cumulative histogram per (route, method) with the last exemplar request
+// ID seen in each bucket, mirroring what an OpenMetrics exemplar would
+// carry.
+type registry struct {
+	mu        sync.Mutex
+	counters  map[counterKey]float64
+	inFlight  map[string]float64
+	durations map[durationKey]*histogram
+}
+
+type histogram struct {
+	buckets   []float64 // cumulative counts, parallel to durationBuckets
+	exemplars []string  // last request ID observed in each bucket
+	count     float64
+	sum       float64
+}
+
+func newRegistry() *registry {
+	return &registry{
+		counters:  make(map[counterKey]float64),
+		inFlight:  make(map[string]float64),
+		durations: make(map[durationKey]*histogram),
+	}
+}
+
+func (reg *registry) incInFlight(route string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.inFlight[route]++
+}
+
+func (reg *registry) decInFlight(route string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.inFlight[route]--
+}
+
+func (reg *registry) observe(route, method string, status int, seconds float64, requestID string) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	reg.counters[counterKey{route, method, strconv.Itoa(status)}]++
+
+	dk := durationKey{route, method}
+	h, ok := reg.durations[dk]
+	if !ok {
+		h = &histogram{
+			buckets:   make([]float64, len(durationBuckets)),
+			exemplars: make([]string, len(durationBuckets)),
+		}
+		reg.durations[dk] = h
+	}
+	h.count++
+	h.sum += seconds
+	for i, le := range durationBuckets {
+		if seconds <= le {
+			h.buckets[i]++
+			if requestID != "" {
+				h.exemplars[i] = requestID
+			}
+		}
+	}
+}
+
+// writeTo renders the registry in the Prometheus text exposition format.
+func (reg *registry) writeTo(w io.Writer) {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	var b strings.Builder
+
+	b.WriteString("# HELP hub_http_requests_total Total number of HTTP requests handled by the Hub API, by route, method and status.\n")
+	b.WriteString("# TYPE hub_http_requests_total counter\n")
+	for _, k := range sortedCounterKeys(reg.counters) {
+		fmt.Fprintf(&b, `hub_http_requests_total{route=%q,method=%q,status=%q} %v`+"\n", k.route, k.method, k.status, reg.counters[k])
+	}
+
+	b.WriteString("# HELP hub_http_in_flight Number of HTTP requests currently being served, by route.\n")
+	b.WriteString("# TYPE hub_http_in_flight gauge\n")
+	for _, route := range sortedStringKeys(reg.inFlight) {
+		fmt.Fprintf(&b, `hub_http_in_flight{route=%q} %v`+"\n", route, reg.inFlight[route])
+	}
+
+	b.WriteString("# HELP hub_http_request_duration_seconds HTTP request duration in seconds, by route and method.\n")
+	b.WriteString("# TYPE hub_http_request_duration_seconds histogram\n")
+	for _, k := range sortedDurationKeys(reg.durations) {
+		h := reg.durations[k]
+		for i, le := range durationBuckets {
+			exemplar := ""
+			if h.exemplars[i] != "" {
+				exemplar = fmt.Sprintf(` # {request_id=%q} %v`, h.exemplars[i], h.buckets[i])
+			}
+			fmt.Fprintf(&b, `hub_http_request_duration_seconds_bucket{route=%q,method=%q,le=%q} %v%s`+"\n",
+				k.route, k.method, strconv.FormatFloat(le, 'g', -1, 64), h.buckets[i], exemplar)
+		}
+		fmt.Fprintf(&b, `hub_http_request_duration_seconds_bucket{route=%q,method=%q,le="+Inf"} %v`+"\n", k.route, k.method, h.count)
+		fmt.Fprintf(&b, `hub_http_request_duration_seconds_sum{route=%q,method=%q} %v`+"\n", k.route, k.method, h.sum)
+		fmt.Fprintf(&b, `hub_http_request_duration_seconds_count{route=%q,method=%q} %v`+"\n", k.route, k.method, h.count)
+	}
+
+	io.WriteString(w, b.String())
+}
+
+func sortedCounterKeys(m map[counterKey]float64) []counterKey {
+	keys := make([]counterKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func sortedDurationKeys(m map[durationKey]*histogram) []durationKey {
+	keys := make([]durationKey, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+	})
+	return keys
+}
+
+func sortedStringKeys(m map[string]float64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
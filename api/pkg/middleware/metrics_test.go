@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+func TestMetrics_RecordsCounterAndInFlight(t *testing.T) {
+	route := "/test/" + t.Name()
+	var duringRequest string
+	h := Metrics(route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		duringRequest = scrapeMetrics(t)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	wantInFlight := `hub_http_in_flight{route="` + route + `"} 1`
+	if !strings.Contains(duringRequest, wantInFlight) {
+		t.Fatalf("expected metrics output during the request to contain %q, got:\n%s", wantInFlight, duringRequest)
+	}
+
+	body := scrapeMetrics(t)
+	want := `hub_http_requests_total{route="` + route + `",method="POST",status="201"} 1`
+	if !strings.Contains(body, want) {
+		t.Fatalf("expected metrics output to contain %q, got:\n%s", want, body)
+	}
+}
+
+func TestMetrics_DefaultsStatusTo200(t *testing.T) {
+	route := "/test/" + t.Name()
+	h := Metrics(route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	body := scrapeMetrics(t)
+	want := `hub_http_requests_total{route="` + route + `",method="GET",status="200"} 1`
+	if !strings.Contains(body, want) {
+		t.Fatalf("expected metrics output to contain %q, got:\n%s", want, body)
+	}
+}
+
+func TestMetrics_AttachesRequestIDExemplar(t *testing.T) {
+	route := "/test/" + t.Name()
+	h := Metrics(route, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	ctx := context.WithValue(r.Context(), goamiddleware.RequestIDKey, "req-abc-123")
+	h.ServeHTTP(httptest.NewRecorder(), r.WithContext(ctx))
+
+	body := scrapeMetrics(t)
+	if !strings.Contains(body, `request_id="req-abc-123"`) {
+		t.Fatalf("expected metrics output to carry the request ID exemplar, got:\n%s", body)
+	}
+}
+
+func TestMetricsHandler_ExposesPrometheusFormat(t *testing.T) {
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusOK)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Fatalf("Content-Type = %q, want a text/plain prefix", ct)
+	}
+	body := w.Body.String()
+	for _, metric := range []string{"hub_http_requests_total", "hub_http_request_duration_seconds", "hub_http_in_flight"} {
+		if !strings.Contains(body, metric) {
+			t.Fatalf("expected /metrics output to mention %q, got:\n%s", metric, body)
+		}
+	}
+}
+
+func scrapeMetrics(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	return w.Body.String()
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"unicode"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+// defaultRequestIDMaxLength rejects an incoming X-Request-Id header longer
+// than this many bytes, falling back to a generated ID instead.
+const defaultRequestIDMaxLength = 128
+
+// IDGenerator produces the request ID used when none is accepted from the
+// incoming request. Defaults to NewUUID.
+type IDGenerator func() string
+
+// RequestIDOptions configures RequestID. The zero value is ready to use:
+// it always generates a new UUIDv4 and ignores any incoming header.
+type RequestIDOptions struct {
+	// UseRequestIDHeader, if true, accepts the incoming X-Request-Id
+	// header when it passes validation instead of always generating a
+	// new ID.
+	UseRequestIDHeader bool
+	// MaxLength rejects an incoming request ID longer than this many
+	// bytes. Defaults to defaultRequestIDMaxLength when <= 0.
+	MaxLength int
+	// Generate produces the request ID used when none is accepted from
+	// the incoming request. Defaults to NewUUID when nil.
+	Generate IDGenerator
+}
+
+// RequestID returns middleware that stores a request ID under
+// goamiddleware.RequestIDKey, the same context key goa's own
+// GenerateRequestID middleware uses, so httperr, the metrics middleware and
+// RequestIDTransport all observe it regardless of which one produced it.
+//
+// Unlike goa's vendored middleware.GenerateRequestID (which defaults to a
+// 6-byte shortID), this generates a collision-resistant UUIDv4 by default
+// and validates any incoming header ID before echoing it back: it must be
+// non-empty, no longer than opts.MaxLength, and made up entirely of
+// printable characters. Incoming IDs are never truncated to fit; they're
+// rejected and replaced by a generated one instead.
+func RequestID(opts RequestIDOptions) func(http.Handler) http.Handler {
+	maxLength := opts.MaxLength
+	if maxLength <= 0 {
+		maxLength = defaultRequestIDMaxLength
+	}
+	generate := opts.Generate
+	if generate == nil {
+		generate = NewUUID
+	}
+
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := ""
+			if opts.UseRequestIDHeader {
+				if candidate := r.Header.Get("X-Request-Id"); isValidRequestID(candidate, maxLength) {
+					id = candidate
+				}
+			}
+			if id == "" {
+				id = generate()
+			}
+			ctx := context.WithValue(r.Context(), goamiddleware.RequestIDKey, id)
+			w.Header().Set("X-Request-Id", id)
+			h.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// isValidRequestID reports whether id is safe to echo back to the caller
+// and log as-is: non-empty, no longer than maxLength bytes, and made up
+// entirely of printable characters.
+func isValidRequestID(id string, maxLength int) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for _, r := range id {
+		if !unicode.IsPrint(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// NewUUID returns a random RFC 4122 version 4 UUID, e.g.
+// "8b6c432f-4b1e-4c1b-9a3a-9f1c2f6a2b0e". It has no dependency beyond the
+// standard library's crypto/rand, so it doesn't require vendoring a new
+// module.
+func NewUUID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(fmt.Sprintf("middleware: failed to read random bytes: %v", err))
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // variant 10
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
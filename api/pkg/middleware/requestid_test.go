@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestNewUUID(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		id := NewUUID()
+		if !uuidPattern.MatchString(id) {
+			t.Fatalf("NewUUID() = %q, want a v4 UUID", id)
+		}
+		if seen[id] {
+			t.Fatalf("NewUUID() produced a duplicate: %q", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestIsValidRequestID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		max  int
+		want bool
+	}{
+		{"empty", "", 128, false},
+		{"printable", "abc-123", 128, true},
+		{"too long", strings.Repeat("a", 129), 128, false},
+		{"exactly max", strings.Repeat("a", 128), 128, true},
+		{"control char", "abc\x00def", 128, false},
+		{"newline", "abc\ndef", 128, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isValidRequestID(tt.id, tt.max); got != tt.want {
+				t.Errorf("isValidRequestID(%q, %d) = %v, want %v", tt.id, tt.max, got, tt.want)
+			}
+		})
+	}
+}
+
+func requestIDFrom(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(goamiddleware.RequestIDKey).(string)
+	return id, ok
+}
+
+func TestRequestID_GeneratesByDefault(t *testing.T) {
+	var gotID string
+	h := RequestID(RequestIDOptions{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id, ok := requestIDFrom(r.Context())
+		if !ok || id == "" {
+			t.Fatal("expected a request ID in context")
+		}
+		gotID = id
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "should-be-ignored-when-header-use-disabled")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if gotID == "should-be-ignored-when-header-use-disabled" {
+		t.Fatal("expected a generated ID, header use is disabled by default")
+	}
+	if w.Header().Get("X-Request-Id") != gotID {
+		t.Fatalf("response header X-Request-Id = %q, want %q", w.Header().Get("X-Request-Id"), gotID)
+	}
+}
+
+func TestRequestID_AcceptsValidIncomingHeader(t *testing.T) {
+	var gotID string
+	h := RequestID(RequestIDOptions{UseRequestIDHeader: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = requestIDFrom(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "client-supplied-id")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if gotID != "client-supplied-id" {
+		t.Fatalf("gotID = %q, want %q", gotID, "client-supplied-id")
+	}
+}
+
+func TestRequestID_RejectsOversizedIncomingHeader(t *testing.T) {
+	var gotID string
+	h := RequestID(RequestIDOptions{UseRequestIDHeader: true, MaxLength: 8})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = requestIDFrom(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "way-too-long-to-be-accepted")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if gotID == "way-too-long-to-be-accepted" {
+		t.Fatal("expected oversized incoming ID to be rejected and replaced")
+	}
+	if !uuidPattern.MatchString(gotID) {
+		t.Fatalf("expected fallback to a generated UUID, got %q", gotID)
+	}
+}
+
+func TestRequestID_RejectsNonPrintableIncomingHeader(t *testing.T) {
+	var gotID string
+	h := RequestID(RequestIDOptions{UseRequestIDHeader: true})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID, _ = requestIDFrom(r.Context())
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Request-Id", "abc\x00def")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	if !uuidPattern.MatchString(gotID) {
+		t.Fatalf("expected fallback to a generated UUID, got %q", gotID)
+	}
+}
@@ -0,0 +1,42 @@
+// Package middleware collects the cross-cutting HTTP concerns shared by the
+// Hub API server: request ID propagation and request metrics.
+package middleware
+
+import (
+	"net/http"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+// RequestIDTransport is an http.RoundTripper that copies the request ID
+// stored under goamiddleware.RequestIDKey in the outgoing request's context
+// onto the X-Request-Id header, so an outbound call made on behalf of an
+// incoming request carries the same ID for end-to-end tracing. There is no
+// outbound HTTP client in this tree yet to plug it into; it's here for the
+// next one (a git provider fetch, a catalog resolver call, a webhook
+// delivery, ...) to use via NewRequestIDClient.
+type RequestIDTransport struct {
+	// Base is the underlying RoundTripper used to perform the request.
+	// Defaults to http.DefaultTransport when nil.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *RequestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if id, ok := req.Context().Value(goamiddleware.RequestIDKey).(string); ok && id != "" {
+		req = req.Clone(req.Context())
+		req.Header.Set("X-Request-Id", id)
+	}
+	return base.RoundTrip(req)
+}
+
+// NewRequestIDClient returns an *http.Client that forwards the request ID
+// from the outgoing request's context as X-Request-Id on every call. Use it
+// for any outbound HTTP client made on behalf of an incoming request.
+func NewRequestIDClient(base http.RoundTripper) *http.Client {
+	return &http.Client{Transport: &RequestIDTransport{Base: base}}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	goamiddleware "goa.design/goa/v3/middleware"
+)
+
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestRequestIDTransport_ForwardsID(t *testing.T) {
+	var gotHeader string
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotHeader = r.Header.Get("X-Request-Id")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	client := NewRequestIDClient(base)
+
+	r, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+	ctx := context.WithValue(r.Context(), goamiddleware.RequestIDKey, "req-xyz")
+	r = r.WithContext(ctx)
+
+	if _, err := client.Do(r); err != nil {
+		t.Fatalf("client.Do() error = %v", err)
+	}
+	if gotHeader != "req-xyz" {
+		t.Fatalf("X-Request-Id = %q, want %q", gotHeader, "req-xyz")
+	}
+}
+
+func TestRequestIDTransport_NoIDInContext(t *testing.T) {
+	var gotHeader string
+	base := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		gotHeader = r.Header.Get("X-Request-Id")
+		return &http.Response{StatusCode: http.StatusOK, Body: http.NoBody}, nil
+	})
+
+	client := NewRequestIDClient(base)
+	r, err := http.NewRequest(http.MethodGet, "http://example.com", nil)
+	if err != nil {
+		t.Fatalf("http.NewRequest() error = %v", err)
+	}
+
+	if _, err := client.Do(r); err != nil {
+		t.Fatalf("client.Do() error = %v", err)
+	}
+	if gotHeader != "" {
+		t.Fatalf("X-Request-Id = %q, want empty when no request ID is in context", gotHeader)
+	}
+}
+
+func TestRequestIDTransport_DefaultsBaseToDefaultTransport(t *testing.T) {
+	transport := &RequestIDTransport{}
+	if transport.Base != nil {
+		t.Fatal("expected zero-value Base")
+	}
+	// RoundTrip should fall back to http.DefaultTransport without panicking;
+	// exercised indirectly via a real client against a local test server.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: transport}
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("client.Get() error = %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent {
+		t.Fatalf("status = %d, want %d", resp.StatusCode, http.StatusNoContent)
+	}
+}